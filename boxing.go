@@ -7,6 +7,7 @@ package nanboxing
 import (
 	"fmt"
 	"math"
+	"sync"
 	"unsafe"
 )
 
@@ -28,6 +29,8 @@ const (
 	TagBool   Tag = 0x3
 	TagArray  Tag = 0x4
 	TagObject Tag = 0x5
+	TagInt64  Tag = 0x6
+	TagUint64 Tag = 0x7
 	TagNull   Tag = 0xF
 )
 
@@ -49,6 +52,82 @@ const (
 	TagShift uint64 = 47
 )
 
+/*
+ TagInt64 and TagUint64 payloads reserve their top bit as an escape flag,
+ leaving 46 data bits: when the flag is clear the data bits hold the
+ value sign-extended (int64) or as-is (uint64) inline; when set, the
+ data bits hold a handleID pointing at the full 64-bit value on the heap.
+*/
+const (
+	int64EscapeBit uint64 = 1 << 46
+	inline46Mask   uint64 = 1<<46 - 1
+)
+
+// handleID indexes into the package-level handle table. Pointer-tagged
+// boxes (string, array, object) store a handleID in their payload instead
+// of a raw address, so the referenced value stays reachable for the GC.
+type handleID uint32
+
+var handleTable = struct {
+	mu    sync.RWMutex
+	slots []any
+	free  []handleID
+}{}
+
+// newHandle stores v in the handle table and returns the slot it occupies.
+// Box is a plain float64 value with no pointer of its own, so the GC cannot
+// observe when the last Box referencing a handle becomes unreachable: there
+// is nothing to attach a finalizer to. Callers that create pointer-tagged
+// boxes (string, array, object) are responsible for calling Release once the
+// box is no longer needed; a Box whose handle is never released leaks its
+// slot for the lifetime of the program.
+func newHandle(v any) handleID {
+	handleTable.mu.Lock()
+	defer handleTable.mu.Unlock()
+	if n := len(handleTable.free); n > 0 {
+		id := handleTable.free[n-1]
+		handleTable.free = handleTable.free[:n-1]
+		handleTable.slots[id] = v
+		return id
+	}
+	handleTable.slots = append(handleTable.slots, v)
+	return handleID(len(handleTable.slots) - 1)
+}
+
+// handle retrieves the value stored at id.
+func handle(id handleID) any {
+	handleTable.mu.RLock()
+	v := handleTable.slots[id]
+	handleTable.mu.RUnlock()
+	return v
+}
+
+// releaseHandle clears a slot and returns it to the free list.
+func releaseHandle(id handleID) {
+	handleTable.mu.Lock()
+	handleTable.slots[id] = nil
+	handleTable.free = append(handleTable.free, id)
+	handleTable.mu.Unlock()
+}
+
+/*
+ Release frees the handle-table slot backing a pointer-tagged box (string,
+ array, object, or out-of-line int64/uint64). There is no finalizer behind
+ a Box: it is a plain float64 with no pointer for the GC to track, so a box
+ that is never released leaks its slot for the life of the program. Callers
+ that create pointer-tagged boxes must call Release when done with them.
+*/
+func Release(b Box) {
+	switch b.Tag() {
+	case tagString, TagArray, TagObject:
+		releaseHandle(handleID(b.Payload()))
+	case TagInt64, TagUint64:
+		if p := b.Payload(); p&int64EscapeBit != 0 {
+			releaseHandle(handleID(p & inline46Mask))
+		}
+	}
+}
+
 /*
  String prints a Box
 */
@@ -104,6 +183,20 @@ func (x *Box) IsFloat64() bool {
 	return !math.IsNaN(float64(*x))
 }
 
+/*
+ NewFloat32 create a float box from a float32 value
+*/
+func NewFloat32(f float32) Box {
+	return NewFloat(float64(f))
+}
+
+/*
+ ToFloat32 retrieve the float32 value of a box, narrowing from float64
+*/
+func (x *Box) ToFloat32() float32 {
+	return float32(x.ToFloat())
+}
+
 /*
  NewNumber create a int32 box
 */
@@ -127,22 +220,116 @@ func (x *Box) IsNumber() bool {
 	return !x.IsFloat64() && (x.Tag() == TagNumber)
 }
 
+/*
+ NewInt64 create an int64 box.
+
+ Values that fit in 46 bits are stored inline in the payload; wider
+ values are kept in the handle table (see NewString) so ToInt64 returns
+ the exact original bit pattern.
+*/
+func NewInt64(n int64) Box {
+	const lo, hi = -(int64(1) << 45), int64(1)<<45 - 1
+	var payload uint64
+	if n >= lo && n <= hi {
+		payload = uint64(n) & inline46Mask
+	} else {
+		payload = int64EscapeBit | (uint64(newHandle(n)) & inline46Mask)
+	}
+	ui64 := NaNMask | (uint64(TagInt64) << TagShift) | payload
+	return *(*Box)(unsafe.Pointer(&ui64))
+}
+
+/*
+ ToInt64 retrieve the int64 value of a box
+*/
+func (x *Box) ToInt64() int64 {
+	p := x.Payload()
+	if p&int64EscapeBit != 0 {
+		return handle(handleID(p & inline46Mask)).(int64)
+	}
+	v := p & inline46Mask
+	return int64(v<<(64-46)) >> (64 - 46)
+}
+
+/*
+ IsInt64 test if a box type is int64
+*/
+func (x *Box) IsInt64() bool {
+	return !x.IsFloat64() && (x.Tag() == TagInt64)
+}
+
+/*
+ NewUint64 create a uint64 box.
+
+ Values that fit in 46 bits are stored inline in the payload; wider
+ values are kept in the handle table (see NewString) so ToUint64 returns
+ the exact original value.
+*/
+func NewUint64(n uint64) Box {
+	var payload uint64
+	if n <= inline46Mask {
+		payload = n
+	} else {
+		payload = int64EscapeBit | (uint64(newHandle(n)) & inline46Mask)
+	}
+	ui64 := NaNMask | (uint64(TagUint64) << TagShift) | payload
+	return *(*Box)(unsafe.Pointer(&ui64))
+}
+
+/*
+ ToUint64 retrieve the uint64 value of a box
+*/
+func (x *Box) ToUint64() uint64 {
+	p := x.Payload()
+	if p&int64EscapeBit != 0 {
+		return handle(handleID(p & inline46Mask)).(uint64)
+	}
+	return p & inline46Mask
+}
+
+/*
+ IsUint64 test if a box type is uint64
+*/
+func (x *Box) IsUint64() bool {
+	return !x.IsFloat64() && (x.Tag() == TagUint64)
+}
+
+/*
+ AsFloat64 converts any numeric box variant (float, number, int64, uint64)
+ to a float64. It panics if the box does not hold a numeric value.
+*/
+func (x *Box) AsFloat64() float64 {
+	switch {
+	case x.IsFloat64():
+		return x.ToFloat()
+	case x.IsNumber():
+		return float64(x.ToNumber())
+	case x.IsInt64():
+		return float64(x.ToInt64())
+	case x.IsUint64():
+		return float64(x.ToUint64())
+	default:
+		panic(fmt.Sprintf("nanboxing: box with tag %X is not numeric", x.Tag()))
+	}
+}
+
 /*
   NewString create a string box
 
- The string pointer is saved. String memory has to be keep by the box caller
+ The string is kept alive in the package handle table, so the box remains
+ valid across GC cycles, in maps, and across function returns.
 */
 func NewString(s string) Box {
-	p := uintptr(unsafe.Pointer(&s))
-	ui64 := NaNMask | (uint64(tagString) << TagShift) | (uint64(p) & PayloadMask)
+	id := newHandle(s)
+	ui64 := NaNMask | (uint64(tagString) << TagShift) | (uint64(id) & PayloadMask)
 	return *(*Box)(unsafe.Pointer(&ui64))
 }
 
 /*
- ToString retrieve the string pointer of a box
+ ToString retrieve the string value of a box
 */
 func (x *Box) ToString() string {
-	return *(*string)(unsafe.Pointer(uintptr(x.Payload())))
+	return handle(handleID(x.Payload())).(string)
 }
 
 /*
@@ -182,19 +369,20 @@ func (x *Box) IsBool() bool {
 /*
  NewArray create an array box
 
- The array is saved as a pointer to a slice
+ The array is kept alive in the package handle table, so the box remains
+ valid across GC cycles, in maps, and across function returns.
 */
 func NewArray(a Array) Box {
-	p := uintptr(unsafe.Pointer(&a))
-	ui64 := NaNMask | (uint64(TagArray) << TagShift) | (uint64(p) & PayloadMask)
+	id := newHandle(a)
+	ui64 := NaNMask | (uint64(TagArray) << TagShift) | (uint64(id) & PayloadMask)
 	return *(*Box)(unsafe.Pointer(&ui64))
 }
 
 /*
- ToArray retrieve the array pointer of a box
+ ToArray retrieve the array value of a box
 */
 func (x *Box) ToArray() Array {
-	return *(*Array)(unsafe.Pointer(uintptr(x.Payload())))
+	return handle(handleID(x.Payload())).(Array)
 }
 
 /*
@@ -207,19 +395,20 @@ func (x *Box) IsArray() bool {
 /*
  NewObject create an object box
 
- The object is saved as a pointer to a map
+ The object is kept alive in the package handle table, so the box remains
+ valid across GC cycles, in maps, and across function returns.
 */
 func NewObject(o Object) Box {
-	p := uintptr(unsafe.Pointer(&o))
-	ui64 := NaNMask | (uint64(TagObject) << TagShift) | (uint64(p) & PayloadMask)
+	id := newHandle(o)
+	ui64 := NaNMask | (uint64(TagObject) << TagShift) | (uint64(id) & PayloadMask)
 	return *(*Box)(unsafe.Pointer(&ui64))
 }
 
 /*
- ToObject retrieve the object pointer of a box
+ ToObject retrieve the object value of a box
 */
 func (x *Box) ToObject() Object {
-	return *(*Object)(unsafe.Pointer(uintptr(x.Payload())))
+	return handle(handleID(x.Payload())).(Object)
 }
 
 /*
@@ -258,6 +447,11 @@ func (x *Box) IsPointer() bool {
 	return !x.IsFloat64() && ((x.Tag() == TagArray) || (x.Tag() == TagObject))
 }
 
+/*
+ ToPointer returns the handle-table id backing a pointer-tagged box, widened
+ to a uintptr. It no longer denotes a memory address; it remains useful as a
+ stable identity for comparing two pointer-tagged boxes.
+*/
 func (x *Box) ToPointer() uintptr {
 	return uintptr(x.Payload())
 }
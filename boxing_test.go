@@ -2,6 +2,7 @@ package nanboxing
 
 import (
 	"math"
+	"runtime"
 	"testing"
 )
 
@@ -70,6 +71,96 @@ func TestNumberBox(t *testing.T) {
 	}
 }
 
+func TestInt64Box(t *testing.T) {
+	{
+		inlineBox := NewInt64(-1000)
+		if false == math.IsNaN(float64(inlineBox)) {
+			t.Error("The box should be NaN")
+		}
+		if -1000 != inlineBox.ToInt64() {
+			t.Error("inlineBox value should be -1000")
+		}
+	}
+	{
+		heapBox := NewInt64(math.MaxInt64)
+		if false == math.IsNaN(float64(heapBox)) {
+			t.Error("The box should be NaN")
+		}
+		if math.MaxInt64 != heapBox.ToInt64() {
+			t.Errorf("heapBox value should be %d", int64(math.MaxInt64))
+		}
+	}
+	{
+		heapBox := NewInt64(math.MinInt64)
+		if math.MinInt64 != heapBox.ToInt64() {
+			t.Errorf("heapBox value should be %d", int64(math.MinInt64))
+		}
+	}
+}
+
+func TestUint64Box(t *testing.T) {
+	{
+		inlineBox := NewUint64(1000)
+		if false == math.IsNaN(float64(inlineBox)) {
+			t.Error("The box should be NaN")
+		}
+		if 1000 != inlineBox.ToUint64() {
+			t.Error("inlineBox value should be 1000")
+		}
+	}
+	{
+		heapBox := NewUint64(math.MaxUint64)
+		if false == math.IsNaN(float64(heapBox)) {
+			t.Error("The box should be NaN")
+		}
+		if uint64(math.MaxUint64) != heapBox.ToUint64() {
+			t.Errorf("heapBox value should be %d", uint64(math.MaxUint64))
+		}
+	}
+}
+
+// TestHeapBoxSurvivesGC forces a GC between creating an out-of-line
+// int64/uint64/string box and reading it back, so a regression that ties
+// handle-table liveness to finalizers on a value never reachable through
+// the Box itself (Box is a plain float64) shows up as a panic here rather
+// than only under real GC pressure.
+func TestHeapBoxSurvivesGC(t *testing.T) {
+	int64Box := NewInt64(math.MaxInt64)
+	uint64Box := NewUint64(math.MaxUint64)
+	stringBox := NewString("hello world")
+
+	runtime.GC()
+
+	if math.MaxInt64 != int64Box.ToInt64() {
+		t.Errorf("int64Box value should be %d", int64(math.MaxInt64))
+	}
+	if uint64(math.MaxUint64) != uint64Box.ToUint64() {
+		t.Errorf("uint64Box value should be %d", uint64(math.MaxUint64))
+	}
+	if "hello world" != stringBox.ToString() {
+		t.Error("stringBox value should be \"hello world\"")
+	}
+}
+
+func TestAsFloat64(t *testing.T) {
+	numberBox := NewNumber(1)
+	if 1 != numberBox.AsFloat64() {
+		t.Error("numberBox.AsFloat64() should be 1")
+	}
+	floatBox := NewFloat(2)
+	if 2 != floatBox.AsFloat64() {
+		t.Error("floatBox.AsFloat64() should be 2")
+	}
+	int64Box := NewInt64(3)
+	if 3 != int64Box.AsFloat64() {
+		t.Error("int64Box.AsFloat64() should be 3")
+	}
+	uint64Box := NewUint64(4)
+	if 4 != uint64Box.AsFloat64() {
+		t.Error("uint64Box.AsFloat64() should be 4")
+	}
+}
+
 func TestObject(t *testing.T) {
 	objectSource := make(Object)
 	b1 := NewNumber(1)
@@ -125,12 +216,12 @@ func TestTag(t *testing.T) {
 	maxPositiveBox := NewNumber(math.MaxInt32)
 	tag = maxPositiveBox.Tag()
 	if TagNumber != tag {
-		t.Errorf("The tag should be Number, box value %x.")
+		t.Errorf("The tag should be Number, box value %x.", math.Float64bits(float64(maxPositiveBox)))
 	}
 	(&maxPositiveBox).SetTag(tagString)
 	tag = maxPositiveBox.Tag()
 	if tagString != tag {
-		t.Errorf("The tag should be String, box value %x.")
+		t.Errorf("The tag should be String, box value %x.", math.Float64bits(float64(maxPositiveBox)))
 	}
 	(&maxPositiveBox).SetTag(TagNumber)
 	tag = maxPositiveBox.Tag()
@@ -146,7 +237,7 @@ func TestPayload(t *testing.T) {
 	}
 	maxPositiveBox.SetPayload(uint64(math.MaxInt32))
 	if TagNumber != maxPositiveBox.Tag() {
-		t.Errorf("The tag should be Number, box value %x.")
+		t.Errorf("The tag should be Number, box value %x.", math.Float64bits(float64(maxPositiveBox)))
 	}
 	if math.MaxInt32 != maxPositiveBox.ToNumber() {
 		t.Errorf("maxPositiveBox value should be %d", math.MaxInt32)
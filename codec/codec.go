@@ -0,0 +1,339 @@
+// Package codec implements a compact binary wire format for nanboxing.Box
+// trees. Encoding and decoding dispatch through a fixed 16-entry table of
+// function pointers indexed by tag, the same table-driven pattern protobuf
+// uses for its per-type marshalInfo, so the hot path never needs a type
+// switch or reflect.
+package codec
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/gsempe/nanboxing"
+)
+
+// wireFloat is the wire tag for an untagged float64 box: nanboxing stores
+// plain floats without a Tag at all, so it needs a slot of its own. The
+// other slots mirror nanboxing.Box.Tag() (TagNumber, TagBool, ... TagNull),
+// including the unexported string tag, whose wire value (2) is pinned here
+// since the wire format must stay stable regardless of internal naming.
+const (
+	wireFloat  = 0x0
+	wireString = 0x2
+)
+
+type marshalFunc func(e *Encoder, b nanboxing.Box) error
+type unmarshalFunc func(d *Decoder) (nanboxing.Box, error)
+
+var marshalers [16]marshalFunc
+var unmarshalers [16]unmarshalFunc
+
+func init() {
+	marshalers[wireFloat] = marshalFloat
+	marshalers[wireString] = marshalString
+	marshalers[nanboxing.TagNumber] = marshalNumber
+	marshalers[nanboxing.TagBool] = marshalBool
+	marshalers[nanboxing.TagArray] = marshalArray
+	marshalers[nanboxing.TagObject] = marshalObject
+	marshalers[nanboxing.TagInt64] = marshalInt64
+	marshalers[nanboxing.TagUint64] = marshalUint64
+	marshalers[nanboxing.TagNull] = marshalNull
+
+	unmarshalers[wireFloat] = unmarshalFloat
+	unmarshalers[wireString] = unmarshalString
+	unmarshalers[nanboxing.TagNumber] = unmarshalNumber
+	unmarshalers[nanboxing.TagBool] = unmarshalBool
+	unmarshalers[nanboxing.TagArray] = unmarshalArray
+	unmarshalers[nanboxing.TagObject] = unmarshalObject
+	unmarshalers[nanboxing.TagInt64] = unmarshalInt64
+	unmarshalers[nanboxing.TagUint64] = unmarshalUint64
+	unmarshalers[nanboxing.TagNull] = unmarshalNull
+}
+
+// wireTag returns the table index for b: wireFloat for an untagged float64
+// box, otherwise b.Tag().
+func wireTag(b nanboxing.Box) byte {
+	if b.IsFloat64() {
+		return wireFloat
+	}
+	return byte(b.Tag())
+}
+
+// Encoder writes a compact binary encoding of Box trees to an io.Writer.
+type Encoder struct {
+	w   io.Writer
+	buf [binary.MaxVarintLen64]byte
+}
+
+// NewEncoder returns an Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes b to the underlying writer.
+func (e *Encoder) Encode(b nanboxing.Box) error {
+	tag := wireTag(b)
+	if _, err := e.w.Write([]byte{tag}); err != nil {
+		return err
+	}
+	fn := marshalers[tag]
+	if fn == nil {
+		return fmt.Errorf("codec: no marshaler for wire tag %#x", tag)
+	}
+	return fn(e, b)
+}
+
+func (e *Encoder) writeUvarint(v uint64) error {
+	n := binary.PutUvarint(e.buf[:], v)
+	_, err := e.w.Write(e.buf[:n])
+	return err
+}
+
+func (e *Encoder) writeBytes(p []byte) error {
+	if err := e.writeUvarint(uint64(len(p))); err != nil {
+		return err
+	}
+	_, err := e.w.Write(p)
+	return err
+}
+
+func marshalFloat(e *Encoder, b nanboxing.Box) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(b.ToFloat()))
+	_, err := e.w.Write(buf[:])
+	return err
+}
+
+func marshalNumber(e *Encoder, b nanboxing.Box) error {
+	return e.writeUvarint(zigzag(int64(b.ToNumber())))
+}
+
+func marshalInt64(e *Encoder, b nanboxing.Box) error {
+	return e.writeUvarint(zigzag(b.ToInt64()))
+}
+
+func marshalUint64(e *Encoder, b nanboxing.Box) error {
+	return e.writeUvarint(b.ToUint64())
+}
+
+func marshalBool(e *Encoder, b nanboxing.Box) error {
+	v := byte(0)
+	if b.ToBool() {
+		v = 1
+	}
+	_, err := e.w.Write([]byte{v})
+	return err
+}
+
+func marshalString(e *Encoder, b nanboxing.Box) error {
+	return e.writeBytes([]byte(b.ToString()))
+}
+
+func marshalNull(e *Encoder, b nanboxing.Box) error {
+	return nil
+}
+
+func marshalArray(e *Encoder, b nanboxing.Box) error {
+	a := b.ToArray()
+	if err := e.writeUvarint(uint64(len(a))); err != nil {
+		return err
+	}
+	for _, elem := range a {
+		if err := e.Encode(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func marshalObject(e *Encoder, b nanboxing.Box) error {
+	o := b.ToObject()
+	if err := e.writeUvarint(uint64(len(o))); err != nil {
+		return err
+	}
+	for k, v := range o {
+		if err := e.writeBytes([]byte(k)); err != nil {
+			return err
+		}
+		if err := e.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// arrayPool and objectPool hold scratch backing storage reused across
+// Decode calls while an array/object is being assembled. The final,
+// right-sized Array/Object handed to NewArray/NewObject is always a fresh
+// copy, so the pooled storage can be recycled safely once copied.
+var arrayPool = sync.Pool{
+	New: func() any {
+		a := make(nanboxing.Array, 0, 16)
+		return &a
+	},
+}
+
+var objectPool = sync.Pool{
+	New: func() any {
+		o := make(nanboxing.Object, 16)
+		return &o
+	},
+}
+
+// Decoder reads the binary encoding produced by Encoder from an io.Reader.
+type Decoder struct {
+	r *bufio.Reader
+}
+
+// NewDecoder returns a Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads and returns the next Box from the underlying reader.
+func (d *Decoder) Decode() (nanboxing.Box, error) {
+	tag, err := d.r.ReadByte()
+	if err != nil {
+		return nanboxing.NewNull(), err
+	}
+	if int(tag) >= len(unmarshalers) || unmarshalers[tag] == nil {
+		return nanboxing.NewNull(), fmt.Errorf("codec: no unmarshaler for wire tag %#x", tag)
+	}
+	return unmarshalers[tag](d)
+}
+
+func (d *Decoder) readUvarint() (uint64, error) {
+	return binary.ReadUvarint(d.r)
+}
+
+func (d *Decoder) readBytes() ([]byte, error) {
+	n, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	p := make([]byte, n)
+	if _, err := io.ReadFull(d.r, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func unmarshalFloat(d *Decoder) (nanboxing.Box, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(d.r, buf[:]); err != nil {
+		return nanboxing.NewNull(), err
+	}
+	return nanboxing.NewFloat(math.Float64frombits(binary.LittleEndian.Uint64(buf[:]))), nil
+}
+
+func unmarshalNumber(d *Decoder) (nanboxing.Box, error) {
+	v, err := d.readUvarint()
+	if err != nil {
+		return nanboxing.NewNull(), err
+	}
+	return nanboxing.NewNumber(int32(unzigzag(v))), nil
+}
+
+func unmarshalInt64(d *Decoder) (nanboxing.Box, error) {
+	v, err := d.readUvarint()
+	if err != nil {
+		return nanboxing.NewNull(), err
+	}
+	return nanboxing.NewInt64(unzigzag(v)), nil
+}
+
+func unmarshalUint64(d *Decoder) (nanboxing.Box, error) {
+	v, err := d.readUvarint()
+	if err != nil {
+		return nanboxing.NewNull(), err
+	}
+	return nanboxing.NewUint64(v), nil
+}
+
+func unmarshalBool(d *Decoder) (nanboxing.Box, error) {
+	v, err := d.r.ReadByte()
+	if err != nil {
+		return nanboxing.NewNull(), err
+	}
+	return nanboxing.NewBool(v != 0), nil
+}
+
+func unmarshalString(d *Decoder) (nanboxing.Box, error) {
+	p, err := d.readBytes()
+	if err != nil {
+		return nanboxing.NewNull(), err
+	}
+	return nanboxing.NewString(string(p)), nil
+}
+
+func unmarshalNull(d *Decoder) (nanboxing.Box, error) {
+	return nanboxing.NewNull(), nil
+}
+
+func unmarshalArray(d *Decoder) (nanboxing.Box, error) {
+	count, err := d.readUvarint()
+	if err != nil {
+		return nanboxing.NewNull(), err
+	}
+
+	scratch := arrayPool.Get().(*nanboxing.Array)
+	elems := (*scratch)[:0]
+	for i := uint64(0); i < count; i++ {
+		elem, err := d.Decode()
+		if err != nil {
+			return nanboxing.NewNull(), err
+		}
+		elems = append(elems, elem)
+	}
+
+	final := make(nanboxing.Array, len(elems))
+	copy(final, elems)
+	*scratch = elems[:0]
+	arrayPool.Put(scratch)
+
+	return nanboxing.NewArray(final), nil
+}
+
+func unmarshalObject(d *Decoder) (nanboxing.Box, error) {
+	count, err := d.readUvarint()
+	if err != nil {
+		return nanboxing.NewNull(), err
+	}
+
+	scratch := objectPool.Get().(*nanboxing.Object)
+	o := *scratch
+	for i := uint64(0); i < count; i++ {
+		key, err := d.readBytes()
+		if err != nil {
+			return nanboxing.NewNull(), err
+		}
+		val, err := d.Decode()
+		if err != nil {
+			return nanboxing.NewNull(), err
+		}
+		o[string(key)] = val
+	}
+
+	final := make(nanboxing.Object, len(o))
+	for k, v := range o {
+		final[k] = v
+	}
+	clear(o)
+	objectPool.Put(scratch)
+
+	return nanboxing.NewObject(final), nil
+}
+
+// zigzag and unzigzag map signed integers to/from unsigned varints the way
+// protobuf does, so small negative numbers stay small on the wire.
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
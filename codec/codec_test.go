@@ -0,0 +1,102 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gsempe/nanboxing"
+)
+
+func roundTrip(t *testing.T, b nanboxing.Box) nanboxing.Box {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(b); err != nil {
+		t.Fatalf("Encode returned an error: %v", err)
+	}
+	got, err := NewDecoder(&buf).Decode()
+	if err != nil {
+		t.Fatalf("Decode returned an error: %v", err)
+	}
+	return got
+}
+
+func TestRoundTripScalars(t *testing.T) {
+	{
+		got := roundTrip(t, nanboxing.NewNull())
+		if false == got.IsNull() {
+			t.Error("got should be null")
+		}
+	}
+	{
+		got := roundTrip(t, nanboxing.NewBool(true))
+		if true != got.ToBool() {
+			t.Error("got should be true")
+		}
+	}
+	{
+		got := roundTrip(t, nanboxing.NewNumber(-42))
+		if -42 != got.ToNumber() {
+			t.Error("got should be -42")
+		}
+	}
+	{
+		got := roundTrip(t, nanboxing.NewInt64(1<<50))
+		if (1 << 50) != got.ToInt64() {
+			t.Error("got should be 1<<50")
+		}
+	}
+	{
+		got := roundTrip(t, nanboxing.NewUint64(1<<50))
+		if (1 << 50) != got.ToUint64() {
+			t.Error("got should be 1<<50")
+		}
+	}
+	{
+		got := roundTrip(t, nanboxing.NewFloat(3.5))
+		if 3.5 != got.ToFloat() {
+			t.Error("got should be 3.5")
+		}
+	}
+	{
+		got := roundTrip(t, nanboxing.NewString("hello"))
+		if "hello" != got.ToString() {
+			t.Error("got should be \"hello\"")
+		}
+	}
+}
+
+func TestRoundTripArray(t *testing.T) {
+	source := nanboxing.Array{nanboxing.NewNumber(1), nanboxing.NewString("two"), nanboxing.NewBool(true)}
+	got := roundTrip(t, nanboxing.NewArray(source))
+	if false == got.IsArray() {
+		t.Error("got should be an array")
+	}
+	target := got.ToArray()
+	if 3 != len(target) {
+		t.Error("target should have 3 elements")
+	}
+	first := target[0]
+	if 1 != first.ToNumber() {
+		t.Error("target[0] should be 1")
+	}
+}
+
+func TestRoundTripObject(t *testing.T) {
+	source := make(nanboxing.Object)
+	source["a"] = nanboxing.NewNumber(1)
+	source["b"] = nanboxing.NewString("two")
+
+	got := roundTrip(t, nanboxing.NewObject(source))
+	if false == got.IsObject() {
+		t.Error("got should be an object")
+	}
+	target := got.ToObject()
+	a := target["a"]
+	if 1 != a.ToNumber() {
+		t.Error("target[\"a\"] should be 1")
+	}
+	b := target["b"]
+	if "two" != b.ToString() {
+		t.Error("target[\"b\"] should be \"two\"")
+	}
+}
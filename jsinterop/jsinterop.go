@@ -0,0 +1,33 @@
+// Package jsinterop bridges nanboxing.Box values and goja.Value, the
+// value type of the goja embeddable JS engine, so a Box tree can be
+// passed into and read back out of scripts run by a *goja.Runtime.
+package jsinterop
+
+import (
+	"github.com/dop251/goja"
+
+	"github.com/gsempe/nanboxing"
+)
+
+/*
+ FromGoja converts a goja.Value into a Box tree.
+
+ v is exported to a plain Go value and handed to nanboxing.NewValue, so
+ numbers, strings, booleans, null/undefined, arrays and plain objects all
+ map to their nanboxing equivalent the same way they would coming from
+ any other Go value.
+*/
+func FromGoja(rt *goja.Runtime, v goja.Value) nanboxing.Box {
+	return nanboxing.NewValue(v.Export())
+}
+
+/*
+ ToGoja converts a Box tree into a goja.Value usable inside scripts run by
+ rt. It is the inverse of FromGoja: b.Interface() produces the plain Go
+ value, and rt.ToValue wraps it the same way it would any other value, so
+ a TagObject box becomes a *goja.Object and a TagArray box becomes a JS
+ array object.
+*/
+func ToGoja(rt *goja.Runtime, b nanboxing.Box) goja.Value {
+	return rt.ToValue(b.Interface())
+}
@@ -0,0 +1,46 @@
+package jsinterop
+
+import (
+	"testing"
+
+	"github.com/dop251/goja"
+
+	"github.com/gsempe/nanboxing"
+)
+
+func TestFromGoja(t *testing.T) {
+	rt := goja.New()
+	v, err := rt.RunString(`({name: "gopher", legs: 4})`)
+	if err != nil {
+		t.Fatalf("RunString returned an error: %v", err)
+	}
+
+	box := FromGoja(rt, v)
+	if false == box.IsObject() {
+		t.Error("box should be an object")
+	}
+	o := box.ToObject()
+	name := o["name"]
+	if "gopher" != name.ToString() {
+		t.Error("box[\"name\"] should be \"gopher\"")
+	}
+}
+
+func TestToGoja(t *testing.T) {
+	rt := goja.New()
+	source := make(nanboxing.Object)
+	source["a"] = nanboxing.NewNumber(1)
+	box := nanboxing.NewObject(source)
+
+	v := ToGoja(rt, box)
+	if err := rt.Set("boxed", v); err != nil {
+		t.Fatalf("Set returned an error: %v", err)
+	}
+	result, err := rt.RunString(`boxed.a`)
+	if err != nil {
+		t.Fatalf("RunString returned an error: %v", err)
+	}
+	if 1 != result.ToInteger() {
+		t.Error("boxed.a should be 1")
+	}
+}
@@ -0,0 +1,190 @@
+package nanboxing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+/*
+ Marshal serializes a Box tree directly to JSON, without building an
+ intermediate any: TagNull maps to null, TagBool maps to true/false, the
+ numeric tags map to a JSON number, tagString maps to a quoted string,
+ TagArray maps to [...], and TagObject maps to {...}.
+*/
+func Marshal(b Box) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := marshalBox(&buf, b); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalBox(buf *bytes.Buffer, b Box) error {
+	switch {
+	case b.IsNull():
+		buf.WriteString("null")
+	case b.IsBool():
+		buf.WriteString(strconv.FormatBool(b.ToBool()))
+	case b.IsNumber():
+		buf.WriteString(strconv.FormatInt(int64(b.ToNumber()), 10))
+	case b.IsInt64():
+		buf.WriteString(strconv.FormatInt(b.ToInt64(), 10))
+	case b.IsUint64():
+		buf.WriteString(strconv.FormatUint(b.ToUint64(), 10))
+	case b.IsString():
+		encoded, err := json.Marshal(b.ToString())
+		if err != nil {
+			return err
+		}
+		buf.Write(encoded)
+	case b.IsArray():
+		buf.WriteByte('[')
+		for i, elem := range b.ToArray() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := marshalBox(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case b.IsObject():
+		buf.WriteByte('{')
+		first := true
+		for k, v := range b.ToObject() {
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			key, err := json.Marshal(k)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := marshalBox(buf, v); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		f := b.ToFloat()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("nanboxing: cannot marshal non-finite float %v to JSON", f)
+		}
+		buf.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return nil
+}
+
+/*
+ Unmarshal parses JSON data into a Box tree, streaming through a
+ json.Decoder and building the tree with NewObject/NewArray so the
+ result is a first-class, dynamic Box.
+*/
+func Unmarshal(data []byte) (Box, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	b, err := decodeValue(dec)
+	if err != nil {
+		return NewNull(), err
+	}
+	return b, nil
+}
+
+func decodeValue(dec *json.Decoder) (Box, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return NewNull(), err
+	}
+	return decodeToken(dec, tok)
+}
+
+func decodeToken(dec *json.Decoder, tok json.Token) (Box, error) {
+	switch v := tok.(type) {
+	case nil:
+		return NewNull(), nil
+	case bool:
+		return NewBool(v), nil
+	case json.Number:
+		return decodeNumber(v)
+	case string:
+		return NewString(v), nil
+	case json.Delim:
+		switch v {
+		case '[':
+			a := Array{}
+			for dec.More() {
+				elem, err := decodeValue(dec)
+				if err != nil {
+					return NewNull(), err
+				}
+				a = append(a, elem)
+			}
+			if _, err := dec.Token(); err != nil { // consume ']'
+				return NewNull(), err
+			}
+			return NewArray(a), nil
+		case '{':
+			o := Object{}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return NewNull(), err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return NewNull(), fmt.Errorf("nanboxing: expected object key, got %v", keyTok)
+				}
+				val, err := decodeValue(dec)
+				if err != nil {
+					return NewNull(), err
+				}
+				o[key] = val
+			}
+			if _, err := dec.Token(); err != nil { // consume '}'
+				return NewNull(), err
+			}
+			return NewObject(o), nil
+		}
+	}
+	return NewNull(), fmt.Errorf("nanboxing: unexpected JSON token %v", tok)
+}
+
+func decodeNumber(n json.Number) (Box, error) {
+	if i, err := n.Int64(); err == nil {
+		if i >= math.MinInt32 && i <= math.MaxInt32 {
+			return NewNumber(int32(i)), nil
+		}
+		return NewInt64(i), nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return NewNull(), err
+	}
+	return NewFloat(f), nil
+}
+
+/*
+ MarshalJSON implements json.Marshaler so a Box composes with
+ encoding/json.
+*/
+func (x Box) MarshalJSON() ([]byte, error) {
+	return Marshal(x)
+}
+
+/*
+ UnmarshalJSON implements json.Unmarshaler so a Box composes with
+ encoding/json.
+*/
+func (x *Box) UnmarshalJSON(data []byte) error {
+	b, err := Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	*x = b
+	return nil
+}
@@ -0,0 +1,120 @@
+package nanboxing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	objectSource := make(Object)
+	objectSource["name"] = NewString("gopher")
+	objectSource["age"] = NewNumber(5)
+	objectSource["pets"] = NewArray(Array{NewString("cat"), NewString("dog")})
+	objectSource["active"] = NewBool(true)
+	objectSource["nickname"] = NewNull()
+	box := NewObject(objectSource)
+
+	data, err := Marshal(box)
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+
+	roundTrip, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal returned an error: %v", err)
+	}
+	if false == roundTrip.IsObject() {
+		t.Error("roundTrip should be an object")
+	}
+	o := roundTrip.ToObject()
+	name := o["name"]
+	if "gopher" != name.ToString() {
+		t.Error("roundTrip[\"name\"] should be \"gopher\"")
+	}
+	age := o["age"]
+	if 5 != age.ToNumber() {
+		t.Error("roundTrip[\"age\"] should be 5")
+	}
+	nickname := o["nickname"]
+	if false == nickname.IsNull() {
+		t.Error("roundTrip[\"nickname\"] should be null")
+	}
+	petsBox := o["pets"]
+	pets := petsBox.ToArray()
+	if 2 != len(pets) {
+		t.Error("roundTrip[\"pets\"] should have 2 elements")
+	}
+	if "cat" != pets[0].ToString() {
+		t.Error("roundTrip[\"pets\"][0] should be \"cat\"")
+	}
+}
+
+func TestUnmarshalScalars(t *testing.T) {
+	{
+		box, err := Unmarshal([]byte("null"))
+		if err != nil {
+			t.Fatalf("Unmarshal returned an error: %v", err)
+		}
+		if false == box.IsNull() {
+			t.Error("box should be null")
+		}
+	}
+	{
+		box, err := Unmarshal([]byte("42"))
+		if err != nil {
+			t.Fatalf("Unmarshal returned an error: %v", err)
+		}
+		if 42 != box.ToNumber() {
+			t.Error("box should be 42")
+		}
+	}
+	{
+		box, err := Unmarshal([]byte("9007199254740993"))
+		if err != nil {
+			t.Fatalf("Unmarshal returned an error: %v", err)
+		}
+		if false == box.IsInt64() {
+			t.Error("box should be an int64")
+		}
+		if 9007199254740993 != box.ToInt64() {
+			t.Error("box should be 9007199254740993")
+		}
+	}
+	{
+		box, err := Unmarshal([]byte("3.14"))
+		if err != nil {
+			t.Fatalf("Unmarshal returned an error: %v", err)
+		}
+		if 3.14 != box.ToFloat() {
+			t.Error("box should be 3.14")
+		}
+	}
+}
+
+func TestMarshalNonFiniteFloat(t *testing.T) {
+	if _, err := Marshal(NewFloat(math.NaN())); err == nil {
+		t.Error("Marshal should error on a NaN float box")
+	}
+	if _, err := Marshal(NewFloat(math.Inf(1))); err == nil {
+		t.Error("Marshal should error on a +Inf float box")
+	}
+	if _, err := Marshal(NewFloat(math.Inf(-1))); err == nil {
+		t.Error("Marshal should error on a -Inf float box")
+	}
+}
+
+func TestBoxJSONInterop(t *testing.T) {
+	box := NewArray(Array{NewNumber(1), NewBool(false)})
+	data, err := box.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON returned an error: %v", err)
+	}
+
+	var decoded Box
+	if err := decoded.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON returned an error: %v", err)
+	}
+	if false == decoded.IsArray() {
+		t.Error("decoded should be an array")
+	}
+}
@@ -0,0 +1,136 @@
+package nanboxing
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// defaultTagName is the struct field tag NewValue looks up when boxing a
+// struct as an Object, mirroring how encoding/json uses the "json" tag.
+const defaultTagName = "nanbox"
+
+/*
+ NewValue boxes an arbitrary Go value using reflection.
+
+ bool maps to NewBool, integer kinds that fit in int32 map to NewNumber,
+ wider signed integers map to NewInt64, wider unsigned integers map to
+ NewUint64, all floats map to NewFloat, string maps to NewString,
+ slices and arrays map to NewArray after recursively boxing each element,
+ map[string]T maps to NewObject, and nil interfaces/pointers map to
+ NewNull. Pointers are dereferenced transparently. Struct values are
+ boxed as an Object, keyed by field name unless overridden by a
+ `nanbox:"..."` struct tag; see NewValueWithOptions to use a different
+ tag name. NewValue panics if v cannot be represented as a Box.
+*/
+func NewValue(v any) Box {
+	return NewValueWithOptions(v, defaultTagName)
+}
+
+/*
+ NewValueWithOptions boxes v like NewValue, but reads struct field names
+ from tagName instead of the default "nanbox" tag.
+*/
+func NewValueWithOptions(v any, tagName string) Box {
+	return boxReflect(reflect.ValueOf(v), tagName)
+}
+
+func boxReflect(rv reflect.Value, tagName string) Box {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return NewNull()
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return NewNull()
+	case reflect.Bool:
+		return NewBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := rv.Int()
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return NewInt64(n)
+		}
+		return NewNumber(int32(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := rv.Uint()
+		if n > math.MaxInt32 {
+			return NewUint64(n)
+		}
+		return NewNumber(int32(n))
+	case reflect.Float32, reflect.Float64:
+		return NewFloat(rv.Float())
+	case reflect.String:
+		return NewString(rv.String())
+	case reflect.Slice, reflect.Array:
+		a := make(Array, rv.Len())
+		for i := range a {
+			a[i] = boxReflect(rv.Index(i), tagName)
+		}
+		return NewArray(a)
+	case reflect.Map:
+		o := make(Object, rv.Len())
+		for _, k := range rv.MapKeys() {
+			o[fmt.Sprintf("%v", k.Interface())] = boxReflect(rv.MapIndex(k), tagName)
+		}
+		return NewObject(o)
+	case reflect.Struct:
+		t := rv.Type()
+		o := make(Object, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue // unexported field
+			}
+			name := field.Name
+			if tag := field.Tag.Get(tagName); tag != "" {
+				name = tag
+			}
+			o[name] = boxReflect(rv.Field(i), tagName)
+		}
+		return NewObject(o)
+	default:
+		panic(fmt.Sprintf("nanboxing: cannot box value of kind %s", rv.Kind()))
+	}
+}
+
+/*
+ Interface walks a Box and returns the equivalent plain Go value: bool,
+ int32, int64, uint64, float64, string, []any, map[string]any, or nil.
+ It is the inverse of NewValue.
+*/
+func (x *Box) Interface() any {
+	switch {
+	case x.IsNull():
+		return nil
+	case x.IsBool():
+		return x.ToBool()
+	case x.IsNumber():
+		return x.ToNumber()
+	case x.IsInt64():
+		return x.ToInt64()
+	case x.IsUint64():
+		return x.ToUint64()
+	case x.IsString():
+		return x.ToString()
+	case x.IsArray():
+		a := x.ToArray()
+		out := make([]any, len(a))
+		for i := range a {
+			out[i] = a[i].Interface()
+		}
+		return out
+	case x.IsObject():
+		o := x.ToObject()
+		out := make(map[string]any, len(o))
+		for k := range o {
+			b := o[k]
+			out[k] = b.Interface()
+		}
+		return out
+	default:
+		return x.ToFloat()
+	}
+}
@@ -0,0 +1,247 @@
+package nanboxing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewValueNumberRouting(t *testing.T) {
+	{
+		box := NewValue(int32(42))
+		if false == box.IsNumber() {
+			t.Error("int32 in range should box as a number")
+		}
+		if 42 != box.ToNumber() {
+			t.Error("box value should be 42")
+		}
+	}
+	{
+		box := NewValue(int64(math.MaxInt32) + 1)
+		if false == box.IsInt64() {
+			t.Error("int64 overflowing int32 should box as int64")
+		}
+		if int64(math.MaxInt32)+1 != box.ToInt64() {
+			t.Errorf("box value should be %d", int64(math.MaxInt32)+1)
+		}
+	}
+	{
+		box := NewValue(uint32(7))
+		if false == box.IsNumber() {
+			t.Error("uint32 in range should box as a number")
+		}
+		if 7 != box.ToNumber() {
+			t.Error("box value should be 7")
+		}
+	}
+	{
+		box := NewValue(uint64(math.MaxInt32) + 1)
+		if false == box.IsUint64() {
+			t.Error("uint64 overflowing int32 should box as uint64")
+		}
+		if uint64(math.MaxInt32)+1 != box.ToUint64() {
+			t.Errorf("box value should be %d", uint64(math.MaxInt32)+1)
+		}
+	}
+}
+
+func TestNewValueFloats(t *testing.T) {
+	{
+		box := NewValue(float32(3.5))
+		if false == box.IsFloat64() {
+			t.Error("float32 should box as a float")
+		}
+		if 3.5 != box.ToFloat() {
+			t.Error("box value should be 3.5")
+		}
+	}
+	{
+		box := NewValue(float64(2.25))
+		if false == box.IsFloat64() {
+			t.Error("float64 should box as a float")
+		}
+		if 2.25 != box.ToFloat() {
+			t.Error("box value should be 2.25")
+		}
+	}
+}
+
+func TestNewValueStringAndBool(t *testing.T) {
+	{
+		box := NewValue("gopher")
+		if false == box.IsString() {
+			t.Error("string should box as a string")
+		}
+		if "gopher" != box.ToString() {
+			t.Error("box value should be \"gopher\"")
+		}
+	}
+	{
+		box := NewValue(true)
+		if false == box.IsBool() {
+			t.Error("bool should box as a bool")
+		}
+		if true != box.ToBool() {
+			t.Error("box value should be true")
+		}
+	}
+}
+
+func TestNewValueSliceAndArray(t *testing.T) {
+	{
+		box := NewValue([]int32{1, 2, 3})
+		if false == box.IsArray() {
+			t.Error("slice should box as an array")
+		}
+		a := box.ToArray()
+		if 3 != len(a) {
+			t.Error("array should have 3 elements")
+		}
+		if 2 != a[1].ToNumber() {
+			t.Error("array[1] should be 2")
+		}
+	}
+	{
+		box := NewValue([2]string{"a", "b"})
+		if false == box.IsArray() {
+			t.Error("array should box as an array")
+		}
+		a := box.ToArray()
+		if "b" != a[1].ToString() {
+			t.Error("array[1] should be \"b\"")
+		}
+	}
+}
+
+func TestNewValueMap(t *testing.T) {
+	box := NewValue(map[string]int32{"one": 1, "two": 2})
+	if false == box.IsObject() {
+		t.Error("map[string]T should box as an object")
+	}
+	o := box.ToObject()
+	one := o["one"]
+	two := o["two"]
+	if 1 != one.ToNumber() {
+		t.Error("o[\"one\"] should be 1")
+	}
+	if 2 != two.ToNumber() {
+		t.Error("o[\"two\"] should be 2")
+	}
+}
+
+func TestNewValueStruct(t *testing.T) {
+	type plain struct {
+		Name       string
+		Age        int32
+		unexported int32
+	}
+	box := NewValue(plain{Name: "gopher", Age: 5, unexported: 1})
+	if false == box.IsObject() {
+		t.Error("struct should box as an object")
+	}
+	o := box.ToObject()
+	name := o["Name"]
+	age := o["Age"]
+	if "gopher" != name.ToString() {
+		t.Error("o[\"Name\"] should be \"gopher\"")
+	}
+	if 5 != age.ToNumber() {
+		t.Error("o[\"Age\"] should be 5")
+	}
+	if 2 != len(o) {
+		t.Error("unexported fields should not be boxed")
+	}
+}
+
+// TestNewValueStructTag pins the current tag-handling behavior: unlike
+// encoding/json, a `nanbox:"name,omitempty"` tag is used verbatim as the
+// key — there is no comma-splitting, so "omitempty" is not special and
+// the key ends up being the whole tag string.
+func TestNewValueStructTag(t *testing.T) {
+	type tagged struct {
+		Name string `nanbox:"n"`
+		Age  int32  `nanbox:"age,omitempty"`
+	}
+	box := NewValue(tagged{Name: "gopher", Age: 5})
+	o := box.ToObject()
+	if _, ok := o["n"]; !ok {
+		t.Error("o[\"n\"] should exist for a plain tag")
+	}
+	if _, ok := o["age,omitempty"]; !ok {
+		t.Error("o[\"age,omitempty\"] should exist: the tag is used verbatim, with no comma-splitting")
+	}
+	if _, ok := o["Age"]; ok {
+		t.Error("o[\"Age\"] should not exist once a tag overrides the field name")
+	}
+}
+
+func TestNewValueWithOptionsCustomTagName(t *testing.T) {
+	type tagged struct {
+		Name string `json:"name"`
+	}
+	box := NewValueWithOptions(tagged{Name: "gopher"}, "json")
+	o := box.ToObject()
+	name := o["name"]
+	if "gopher" != name.ToString() {
+		t.Error("o[\"name\"] should be \"gopher\" when tagName is \"json\"")
+	}
+}
+
+func TestNewValuePointerAndInterfaceDeref(t *testing.T) {
+	{
+		n := int32(9)
+		box := NewValue(&n)
+		if false == box.IsNumber() {
+			t.Error("pointer should deref transparently to a number box")
+		}
+		if 9 != box.ToNumber() {
+			t.Error("box value should be 9")
+		}
+	}
+	{
+		var p *int32
+		box := NewValue(p)
+		if false == box.IsNull() {
+			t.Error("nil pointer should box as null")
+		}
+	}
+	{
+		var v any
+		box := NewValue(v)
+		if false == box.IsNull() {
+			t.Error("nil interface should box as null")
+		}
+	}
+}
+
+func TestNewValueInterfaceRoundTrip(t *testing.T) {
+	source := map[string]any{
+		"name":   "gopher",
+		"age":    int32(5),
+		"active": true,
+		"pets":   []int32{1, 2},
+	}
+	box := NewValue(source)
+	got := box.Interface().(map[string]any)
+	if "gopher" != got["name"] {
+		t.Error("round-tripped name should be \"gopher\"")
+	}
+	if int32(5) != got["age"] {
+		t.Error("round-tripped age should be 5")
+	}
+	if true != got["active"] {
+		t.Error("round-tripped active should be true")
+	}
+	pets, ok := got["pets"].([]any)
+	if !ok || 2 != len(pets) {
+		t.Error("round-tripped pets should be a 2-element slice")
+	}
+}
+
+func TestNewValuePanicsOnUnsupportedKind(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewValue should panic on a channel value")
+		}
+	}()
+	NewValue(make(chan int))
+}